@@ -3,19 +3,50 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"sales-analysis/internal/analyzer"
 )
 
 func main() {
+	// Dispatch to the "query" subcommand when present; otherwise run the
+	// default summary report over the whole file.
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		runQuery(os.Args[2:])
+		return
+	}
+	runReport(os.Args[1:])
+}
+
+func runReport(args []string) {
 
 	// 1. Argument Declaration and Parsing using the flag package
+	fs := flag.NewFlagSet("sales-analysis", flag.ExitOnError)
 
 	// Declare the "file" flag with a default value and usage description.
-	filePath := flag.String("file", "data/sales.csv", "Path to the CSV sales data file")
+	filePath := fs.String("file", "data/sales.csv", "Path to the CSV sales data file")
+
+	// format controls how the report is rendered: the original human-readable
+	// text, a single pretty-printed JSON document, or newline-delimited JSON.
+	format := fs.String("format", "text", "Output format: text|json|ndjson")
+
+	// output optionally redirects the report to a file instead of stdout.
+	output := fs.String("output", "", "Path to write the report to (defaults to stdout)")
+
+	// delimiter overrides the CSV field separator, e.g. ";" for European CSVs.
+	delimiter := fs.String("delimiter", ",", "CSV field delimiter")
+
+	// encoding names the source file's text encoding.
+	sourceEncoding := fs.String("encoding", "utf-8", "Source file encoding: utf-8|gbk|latin1")
+
+	// dateLayout, if set, additionally parses Date into SaleRecord.ParsedDate.
+	dateLayout := fs.String("date-layout", "", "time.Parse layout used to parse the Date column, e.g. 2006-01-02")
+
+	// rejects optionally writes every skipped row, with its error, to a CSV file.
+	rejects := fs.String("rejects", "", "Path to write skipped rows as a CSV file")
 
 	// Parse the command-line arguments, populating the filePath variable.
-	flag.Parse()
+	fs.Parse(args)
 
 	// Check if the file path is empty (though it has a default value, this ensures robustness)
 	if *filePath == "" {
@@ -24,15 +55,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 2. File Parsing and Critical Error Handling
+	comma := ','
+	if len(*delimiter) > 0 {
+		comma = []rune(*delimiter)[0]
+	}
 
-	// flag.String returns a *string, so we dereference it using *
-	records, err := analyzer.ParseCSV(*filePath)
+	// 2. File Parsing and Critical Error Handling
+	var rowErrors []analyzer.RowError
+	records, err := analyzer.ParseCSVWithOptions(*filePath, analyzer.ParseOptions{
+		Comma:      comma,
+		Encoding:   *sourceEncoding,
+		DateLayout: *dateLayout,
+		ErrorHandler: func(rowErr analyzer.RowError) error {
+			rowErrors = append(rowErrors, rowErr)
+			return nil
+		},
+	})
 	if err != nil {
 		fmt.Printf("Critical parsing error: %v\n", err)
 		os.Exit(1)
 	}
 
+	if len(rowErrors) > 0 {
+		fmt.Printf("Skipped %d malformed row(s) while parsing '%s'.\n", len(rowErrors), *filePath)
+		if *rejects != "" {
+			rejectsFile, err := os.Create(*rejects)
+			if err != nil {
+				fmt.Printf("Error creating rejects file %s: %v\n", *rejects, err)
+				os.Exit(1)
+			}
+			defer rejectsFile.Close()
+			if err := analyzer.WriteRejectsCSV(rejectsFile, rowErrors); err != nil {
+				fmt.Printf("Error writing rejects file %s: %v\n", *rejects, err)
+				os.Exit(1)
+			}
+		}
+	}
+
 	// Check if any valid records were found
 	if len(records) == 0 {
 		fmt.Printf("File '%s' read successfully, but no valid records were found for analysis.\n", *filePath)
@@ -42,11 +101,114 @@ func main() {
 	// 3. Data Analysis
 	result := analyzer.AnalyzeData(records)
 
-	// 4. Print Results
-	fmt.Println("--- Sales Record Analysis Report ---")
-	fmt.Printf("File Processed: %s\n", *filePath)
-	fmt.Printf("Total Valid Transactions: %d\n", result.TotalTransactions)
-	fmt.Printf("Total Revenue: %.2f $\n", result.TotalRevenue)
-	fmt.Printf("Most Popular Product: %s (sold %d units)\n", result.MostPopularProduct, result.MaxQuantitySoldUnits)
-	fmt.Println("------------------------------------")
+	// 4. Resolve the output destination
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error creating output file %s: %v\n", *output, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// 5. Write Results in the requested format
+	switch *format {
+	case "json":
+		if err := analyzer.WriteJSON(w, result, records); err != nil {
+			fmt.Printf("Error writing JSON report: %v\n", err)
+			os.Exit(1)
+		}
+	case "ndjson":
+		if err := analyzer.WriteNDJSON(w, records); err != nil {
+			fmt.Printf("Error writing NDJSON report: %v\n", err)
+			os.Exit(1)
+		}
+	case "text":
+		fmt.Fprintln(w, "--- Sales Record Analysis Report ---")
+		fmt.Fprintf(w, "File Processed: %s\n", *filePath)
+		fmt.Fprintf(w, "Total Valid Transactions: %d\n", result.TotalTransactions)
+		fmt.Fprintf(w, "Total Revenue: %.2f $\n", result.TotalRevenue)
+		fmt.Fprintf(w, "Most Popular Product: %s (sold %d units)\n", result.MostPopularProduct, result.MaxQuantitySoldUnits)
+		fmt.Fprintln(w, "------------------------------------")
+	default:
+		fmt.Printf("Error: unknown format %q, expected text|json|ndjson\n", *format)
+		os.Exit(1)
+	}
+}
+
+// runQuery implements the "query" subcommand: it parses a SELECT ... query
+// string via analyzer.ParseQuery, runs it against the records read from
+// --file, and writes the resulting table in the requested format.
+func runQuery(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+
+	filePath := fs.String("file", "data/sales.csv", "Path to the CSV sales data file")
+	format := fs.String("format", "text", "Output format: text|csv|json")
+	output := fs.String("output", "", "Path to write the result to (defaults to stdout)")
+	delimiter := fs.String("delimiter", ",", "CSV field delimiter")
+	sourceEncoding := fs.String("encoding", "utf-8", "Source file encoding: utf-8|gbk|latin1")
+	dateLayout := fs.String("date-layout", "", "time.Parse layout used to parse the Date column, e.g. 2006-01-02")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Println("Error: query requires exactly one SELECT statement argument.")
+		fmt.Println(`Usage: sales-analysis query --file=<path/to/file.csv> "SELECT product, SUM(quantity*price) AS revenue ..."`)
+		os.Exit(1)
+	}
+
+	query, err := analyzer.ParseQuery(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error parsing query: %v\n", err)
+		os.Exit(1)
+	}
+
+	comma := ','
+	if len(*delimiter) > 0 {
+		comma = []rune(*delimiter)[0]
+	}
+
+	records, err := analyzer.ParseCSVWithOptions(*filePath, analyzer.ParseOptions{
+		Comma:      comma,
+		Encoding:   *sourceEncoding,
+		DateLayout: *dateLayout,
+	})
+	if err != nil {
+		fmt.Printf("Critical parsing error: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := analyzer.Execute(records, query)
+	if err != nil {
+		fmt.Printf("Error executing query: %v\n", err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error creating output file %s: %v\n", *output, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		err = analyzer.WriteQueryResultJSON(w, result)
+	case "csv":
+		err = analyzer.WriteQueryResultCSV(w, result)
+	case "text":
+		err = analyzer.WriteQueryResultText(w, result)
+	default:
+		fmt.Printf("Error: unknown format %q, expected text|csv|json\n", *format)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Error writing query result: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,80 @@
+package analyzer
+
+import "sort"
+
+// accumulator aggregates per-product revenue and quantity totals. It is the
+// shared core of AnalyzeData, AnalyzeStream, and AnalyzeDataParallel: each
+// adds records independently and accumulators merge by simple summation, so
+// sequential and sharded analysis agree exactly on quantities. Revenue is a
+// float64 sum whose rounding depends on summation order, so sharded revenue
+// totals can differ from sequential ones in the last bits.
+type accumulator struct {
+	revenue  map[string]float64
+	quantity map[string]int
+}
+
+func newAccumulator() *accumulator {
+	return &accumulator{
+		revenue:  make(map[string]float64),
+		quantity: make(map[string]int),
+	}
+}
+
+// add folds a single record into the accumulator.
+func (a *accumulator) add(record SaleRecord) {
+	a.revenue[record.Product] += float64(record.Quantity) * record.Price
+	a.quantity[record.Product] += record.Quantity
+}
+
+// merge folds another accumulator's totals into a, leaving other unchanged.
+func (a *accumulator) merge(other *accumulator) {
+	for product, revenue := range other.revenue {
+		a.revenue[product] += revenue
+	}
+	for product, quantity := range other.quantity {
+		a.quantity[product] += quantity
+	}
+}
+
+// result builds the final AnalysisResult for totalTransactions records.
+// Products are always visited in name order so that the most popular product
+// and TopN are deterministic, independent of map iteration order or the
+// order shards were merged in.
+func (a *accumulator) result(totalTransactions int) AnalysisResult {
+	products := make([]string, 0, len(a.quantity))
+	for product := range a.quantity {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+
+	result := AnalysisResult{
+		TotalTransactions: totalTransactions,
+		RevenueByProduct:  a.revenue,
+		QuantityByProduct: a.quantity,
+	}
+
+	for _, product := range products {
+		result.TotalRevenue += a.revenue[product]
+		if a.quantity[product] > result.MaxQuantitySoldUnits {
+			result.MaxQuantitySoldUnits = a.quantity[product]
+			result.MostPopularProduct = product
+		}
+	}
+
+	stats := make([]ProductStat, len(products))
+	for i, product := range products {
+		stats[i] = ProductStat{Product: product, Quantity: a.quantity[product], Revenue: a.revenue[product]}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Revenue != stats[j].Revenue {
+			return stats[i].Revenue > stats[j].Revenue
+		}
+		return stats[i].Product < stats[j].Product
+	})
+	if len(stats) > topNSize {
+		stats = stats[:topNSize]
+	}
+	result.TopN = stats
+
+	return result
+}
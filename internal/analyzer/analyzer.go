@@ -1,36 +1,56 @@
 package analyzer
 
 import (
-	"encoding/csv"
 	"fmt"
-	"io"
 	"os"
-	"strconv"
+	"time"
 )
 
 // SaleRecord represents a single sales record from the CSV file.
 type SaleRecord struct {
-	Date     string  // Transaction date (e.g., "2023-10-25")
-	Product  string  // Name of the product sold
-	Quantity int     // Quantity of units sold
-	Price    float64 // Price per unit
+	Date       string    `json:"date"`        // Transaction date (e.g., "2023-10-25")
+	Product    string    `json:"product"`     // Name of the product sold
+	Quantity   int       `json:"quantity"`    // Quantity of units sold
+	Price      float64   `json:"price"`       // Price per unit
+	ParsedDate time.Time `json:"parsed_date"` // Date parsed via ParseOptions.DateLayout; zero value if no layout was configured
 }
 
 // AnalysisResult contains the aggregated results of the data analysis.
 type AnalysisResult struct {
-	TotalTransactions    int
-	TotalRevenue         float64
-	MostPopularProduct   string // Name of the product with the highest total quantity sold
-	MaxQuantitySoldUnits int    // Max quantity of units sold for that product
+	TotalTransactions    int     `json:"total_transactions"`
+	TotalRevenue         float64 `json:"total_revenue"`
+	MostPopularProduct   string  `json:"most_popular_product"`    // Name of the product with the highest total quantity sold
+	MaxQuantitySoldUnits int     `json:"max_quantity_sold_units"` // Max quantity of units sold for that product
+
+	RevenueByProduct  map[string]float64 `json:"revenue_by_product"`  // Total revenue per product
+	QuantityByProduct map[string]int     `json:"quantity_by_product"` // Total units sold per product
+	TopN              []ProductStat      `json:"top_n"`               // Top products by revenue, highest first
 }
 
+// ProductStat summarizes a single product's contribution to an AnalysisResult.
+type ProductStat struct {
+	Product  string  `json:"product"`
+	Quantity int     `json:"quantity"`
+	Revenue  float64 `json:"revenue"`
+}
+
+// topNSize bounds how many entries AnalysisResult.TopN holds.
+const topNSize = 10
+
 // --- CSV PROCESSING FUNCTIONS ---
 
 // ParseCSV reads data from the file specified by filePath,
 // parses it into SaleRecord structs, and returns a slice of valid records.
 // It skips records with invalid data (e.g., non-numeric quantity or price)
-// and handles file I/O errors.
+// and handles file I/O errors. It is a thin wrapper around
+// ParseCSVWithOptions using the default CSV dialect.
 func ParseCSV(filePath string) ([]SaleRecord, error) {
+	return ParseCSVWithOptions(filePath, ParseOptions{})
+}
+
+// ParseCSVWithOptions behaves like ParseCSV but lets callers configure the
+// CSV dialect, encoding, column layout, and streaming bounds via opts.
+func ParseCSVWithOptions(filePath string, opts ParseOptions) ([]SaleRecord, error) {
 	// 1. Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -39,94 +59,59 @@ func ParseCSV(filePath string) ([]SaleRecord, error) {
 	// The defer statement ensures that the file is closed when the function exits.
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	// Setting FieldsPerRecord to -1 allows reading records with a variable number of fields.
-	// This lets our code handle missing/extra fields gracefully instead of relying on a critical CSV error.
-	reader.FieldsPerRecord = -1
 	records := []SaleRecord{}
-
-	// Skip the header (first line)
-	if _, err := reader.Read(); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("error reading CSV header: %w", err)
-	}
-
-	// 2. Iterate and parse rows
-	for lineNumber := 2; ; lineNumber++ { // Start from line 2 (after header)
-		row, err := reader.Read()
-		if err == io.EOF {
-			break // End of file reached
-		}
-		if err != nil {
-			// Handle critical I/O errors that are not EOF
-			return nil, fmt.Errorf("critical error reading line %d: %w", lineNumber, err)
-		}
-
-		// Ensure the record has the expected 4 columns. If not, skip it.
-		if len(row) != 4 {
-			fmt.Printf("Warning: line %d skipped - incorrect number of fields (%d instead of 4)\n", lineNumber, len(row))
-			continue
-		}
-
-		// Parse Quantity (string to int)
-		quantity, err := strconv.Atoi(row[2])
-		if err != nil {
-			fmt.Printf("Warning: line %d skipped - error parsing Quantity (%s): %v\n", lineNumber, row[2], err)
-			continue
-		}
-
-		// Parse Price (string to float64)
-		price, err := strconv.ParseFloat(row[3], 64)
-		if err != nil {
-			// Note: strconv.ParseFloat handles "NaN" and "Inf" without error.
-			// We only skip on true parsing errors (e.g., "invalid_price").
-			fmt.Printf("Warning: line %d skipped - error parsing Price (%s): %v\n", lineNumber, row[3], err)
-			continue
-		}
-
-		// Successful record creation only occurs after all validation checks pass.
-		record := SaleRecord{
-			Date:     row[0],
-			Product:  row[1],
-			Quantity: quantity,
-			Price:    price,
-		}
+	reader := NewReader(file, opts)
+	if err := reader.Each(func(record SaleRecord) error {
 		records = append(records, record)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return records, nil
 }
 
+// ParseCSVWithErrors behaves like ParseCSV, but instead of printing each
+// skipped row as a warning, it collects them as RowErrors and returns them
+// alongside the valid records, letting callers summarize or persist rejects.
+func ParseCSVWithErrors(filePath string) ([]SaleRecord, []RowError, error) {
+	var rowErrors []RowError
+	records, err := ParseCSVWithOptions(filePath, ParseOptions{
+		ErrorHandler: func(rowErr RowError) error {
+			rowErrors = append(rowErrors, rowErr)
+			return nil
+		},
+	})
+	return records, rowErrors, err
+}
+
 // --- DATA ANALYSIS FUNCTION ---
 
 // AnalyzeData performs analysis on the provided slice of SaleRecord structs.
 // It calculates the total revenue and identifies the most popular product
-// based on the total quantity of units sold.
+// based on the total quantity of units sold. It is the sequential reference
+// implementation; AnalyzeDataParallel shards the same aggregation across
+// workers. Its counts and quantities are identical to this function's, but
+// its revenue totals can differ by floating-point rounding when prices are
+// fractional, so the two results are not bit-identical in general.
 func AnalyzeData(records []SaleRecord) AnalysisResult {
-	result := AnalysisResult{
-		TotalTransactions:    len(records),
-		TotalRevenue:         0.0,
-		MaxQuantitySoldUnits: 0,
-	}
-
-	// productQuantities aggregates the total units sold for each product.
-	productQuantities := make(map[string]int)
-
-	// 1. Calculate Total Revenue and aggregate quantity
+	acc := newAccumulator()
 	for _, record := range records {
-		// Calculate Total Revenue (Quantity * Price)
-		result.TotalRevenue += float64(record.Quantity) * record.Price
-
-		// Aggregate quantity sold for each product
-		productQuantities[record.Product] += record.Quantity
+		acc.add(record)
 	}
+	return acc.result(len(records))
+}
 
-	// 2. Determine the Most Popular Product
-	for product, totalQuantity := range productQuantities {
-		if totalQuantity > result.MaxQuantitySoldUnits {
-			result.MaxQuantitySoldUnits = totalQuantity
-			result.MostPopularProduct = product
-		}
+// AnalyzeStream performs the same aggregation as AnalyzeData, but consumes
+// records from a channel instead of a slice. This lets very large CSVs be
+// analyzed, e.g. via Reader.Each feeding the channel, without ever
+// materializing every record in memory at once.
+func AnalyzeStream(ch <-chan SaleRecord) AnalysisResult {
+	acc := newAccumulator()
+	total := 0
+	for record := range ch {
+		acc.add(record)
+		total++
 	}
-
-	return result
+	return acc.result(total)
 }
@@ -140,9 +140,9 @@ func TestAnalyzeData(t *testing.T) {
 		{
 			name: "Success_NormalData",
 			inputRecords: []analyzer.SaleRecord{
-				{"2023", "ProductA", 10, 5.0}, // Rev 50.0
-				{"2023", "ProductB", 5, 20.0}, // Rev 100.0
-				{"2023", "ProductA", 2, 5.0},  // Rev 10.0
+				{Date: "2023", Product: "ProductA", Quantity: 10, Price: 5.0},  // Rev 50.0
+				{Date: "2023", Product: "ProductB", Quantity: 5, Price: 20.0},  // Rev 100.0
+				{Date: "2023", Product: "ProductA", Quantity: 2, Price: 5.0},   // Rev 10.0
 			},
 			expected: analyzer.AnalysisResult{
 				TotalTransactions:    3,
@@ -164,7 +164,7 @@ func TestAnalyzeData(t *testing.T) {
 		{
 			name: "Success_SingleRecord",
 			inputRecords: []analyzer.SaleRecord{
-				{"2023", "ProductZ", 1, 99.99},
+				{Date: "2023", Product: "ProductZ", Quantity: 1, Price: 99.99},
 			},
 			expected: analyzer.AnalysisResult{
 				TotalTransactions:    1,
@@ -0,0 +1,118 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+
+	"sales-analysis/internal/analyzer"
+)
+
+// TestReaderEach_SemicolonDelimiter verifies that Comma lets a Reader parse
+// European-style CSVs that use ';' as the field separator.
+func TestReaderEach_SemicolonDelimiter(t *testing.T) {
+	csvContent := "Date;Product;Quantity;Price\n" +
+		"2023-10-01;Laptop;2;1200.50\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{Comma: ';'})
+
+	var got []analyzer.SaleRecord
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		got = append(got, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Product != "Laptop" {
+		t.Fatalf("Each() got %v, want a single Laptop record", got)
+	}
+}
+
+// TestReaderEach_HeaderMap verifies that HeaderMap remaps arbitrary column
+// names to the required fields regardless of their order in the file.
+func TestReaderEach_HeaderMap(t *testing.T) {
+	csvContent := "Producto,Fecha,Precio,Cantidad\n" +
+		"Mouse,2023-10-02,25.99,10\n"
+
+	opts := analyzer.ParseOptions{
+		HeaderMap: map[string]string{
+			"Producto": "Product",
+			"Fecha":    "Date",
+			"Precio":   "Price",
+			"Cantidad": "Quantity",
+		},
+	}
+	reader := analyzer.NewReader(strings.NewReader(csvContent), opts)
+
+	var got []analyzer.SaleRecord
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		got = append(got, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Each() got %d records, want 1", len(got))
+	}
+	want := analyzer.SaleRecord{Date: "2023-10-02", Product: "Mouse", Quantity: 10, Price: 25.99}
+	if got[0].Date != want.Date || got[0].Product != want.Product || got[0].Quantity != want.Quantity || got[0].Price != want.Price {
+		t.Errorf("Each() got %+v, want %+v", got[0], want)
+	}
+}
+
+// TestReaderEach_DateLayout verifies that DateLayout populates ParsedDate and
+// that rows with an unparsable date are skipped like any other bad field.
+func TestReaderEach_DateLayout(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Laptop,2,1200.50\n" +
+		"not-a-date,Mouse,10,25.99\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{DateLayout: "2006-01-02"})
+
+	var got []analyzer.SaleRecord
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		got = append(got, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Each() got %d records, want 1 (bad date skipped)", len(got))
+	}
+	want := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	if !got[0].ParsedDate.Equal(want) {
+		t.Errorf("ParsedDate got %v, want %v", got[0].ParsedDate, want)
+	}
+}
+
+// TestReaderEach_GBKEncoding verifies that Encoding: "gbk" correctly decodes
+// a GBK-encoded source file before parsing.
+func TestReaderEach_GBKEncoding(t *testing.T) {
+	utf8Content := streamHeader + "2023-10-05,笔记本电脑,1,999.00\n"
+
+	gbkBytes, _, err := transform.Bytes(simplifiedchinese.GBK.NewEncoder(), []byte(utf8Content))
+	if err != nil {
+		t.Fatalf("failed to encode test fixture as GBK: %v", err)
+	}
+
+	reader := analyzer.NewReader(bytes.NewReader(gbkBytes), analyzer.ParseOptions{Encoding: "gbk"})
+
+	var got []analyzer.SaleRecord
+	if err := reader.Each(func(record analyzer.SaleRecord) error {
+		got = append(got, record)
+		return nil
+	}); err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+
+	if len(got) != 1 || got[0].Product != "笔记本电脑" {
+		t.Fatalf("Each() got %+v, want a single decoded Product", got)
+	}
+}
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonReport is the document written by WriteJSON. It bundles the aggregated
+// analysis alongside the records it was computed from, so downstream tools
+// can consume a single self-describing payload.
+type jsonReport struct {
+	Analysis AnalysisResult `json:"analysis"`
+	Records  []SaleRecord   `json:"records"`
+}
+
+// WriteJSON encodes result and records as a single, pretty-printed JSON
+// document and writes it to w. The output is indented for readability since
+// it is intended for humans inspecting files as well as downstream tools.
+func WriteJSON(w io.Writer, result AnalysisResult, records []SaleRecord) error {
+	// Encode a nil slice as [] rather than null.
+	if records == nil {
+		records = []SaleRecord{}
+	}
+	report := jsonReport{
+		Analysis: result,
+		Records:  records,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteNDJSON writes records to w as newline-delimited JSON, one compact
+// object per line. This keeps memory usage flat for large files since each
+// record is marshaled and flushed independently instead of being buffered
+// into a single document.
+func WriteNDJSON(w io.Writer, records []SaleRecord) error {
+	enc := json.NewEncoder(w)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sales-analysis/internal/analyzer"
+)
+
+// TestWriteJSON verifies that WriteJSON emits a single, pretty-printed
+// document containing both the analysis and the underlying records.
+func TestWriteJSON(t *testing.T) {
+	records := []analyzer.SaleRecord{
+		{Date: "2023-10-01", Product: "Laptop", Quantity: 2, Price: 1200.50},
+		{Date: "2023-10-02", Product: "Mouse", Quantity: 10, Price: 25.99},
+	}
+	result := analyzer.AnalyzeData(records)
+
+	var buf bytes.Buffer
+	if err := analyzer.WriteJSON(&buf, result, records); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	// Pretty output is indented, unlike the compact NDJSON lines below.
+	if !strings.Contains(buf.String(), "\n  \"analysis\"") {
+		t.Fatalf("WriteJSON() output is not indented, got:\n%s", buf.String())
+	}
+
+	var decoded struct {
+		Analysis analyzer.AnalysisResult `json:"analysis"`
+		Records  []analyzer.SaleRecord   `json:"records"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode WriteJSON() output: %v", err)
+	}
+	if decoded.Analysis.TotalTransactions != result.TotalTransactions {
+		t.Errorf("TotalTransactions got %d, want %d", decoded.Analysis.TotalTransactions, result.TotalTransactions)
+	}
+	if len(decoded.Records) != len(records) {
+		t.Errorf("Records got %d, want %d", len(decoded.Records), len(records))
+	}
+}
+
+// TestWriteJSON_EmptyResultSet ensures an empty records slice still produces
+// a valid, well-formed document rather than an error or null fields.
+func TestWriteJSON_EmptyResultSet(t *testing.T) {
+	var buf bytes.Buffer
+	result := analyzer.AnalyzeData(nil)
+	if err := analyzer.WriteJSON(&buf, result, nil); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded struct {
+		Records []analyzer.SaleRecord `json:"records"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode WriteJSON() output: %v", err)
+	}
+	if len(decoded.Records) != 0 {
+		t.Errorf("Records got %d, want 0", len(decoded.Records))
+	}
+}
+
+// TestWriteNDJSON verifies that each record is emitted as its own compact
+// JSON object on its own line, which is what lets large files be streamed
+// without buffering the whole document in memory.
+func TestWriteNDJSON(t *testing.T) {
+	records := []analyzer.SaleRecord{
+		{Date: "2023-10-01", Product: "Laptop", Quantity: 2, Price: 1200.50},
+		{Date: "2023-10-02", Product: "Mouse", Quantity: 10, Price: 25.99},
+		{Date: "2023-10-03", Product: "Keyboard", Quantity: 5, Price: 45.00},
+	}
+
+	var buf bytes.Buffer
+	if err := analyzer.WriteNDJSON(&buf, records); err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(records) {
+		t.Fatalf("WriteNDJSON() got %d lines, want %d", len(lines), len(records))
+	}
+
+	for i, line := range lines {
+		if strings.Contains(line, "\n") || strings.HasPrefix(line, " ") {
+			t.Errorf("line %d is not compact: %q", i, line)
+		}
+		var decoded analyzer.SaleRecord
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("line %d failed to decode: %v", i, err)
+		}
+		if decoded.Product != records[i].Product {
+			t.Errorf("line %d Product got %s, want %s", i, decoded.Product, records[i].Product)
+		}
+	}
+}
+
+// TestWriteNDJSON_EmptyResultSet ensures no output (and no error) is produced
+// when there are no records to write.
+func TestWriteNDJSON_EmptyResultSet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := analyzer.WriteNDJSON(&buf, nil); err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteNDJSON() got %q, want empty output", buf.String())
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"runtime"
+	"sync"
+)
+
+// AnalyzeDataParallel performs the same aggregation as AnalyzeData, but
+// shards records across workers goroutines, each computing a partial
+// accumulator before they are merged sequentially. If workers is 0 or
+// negative, it defaults to runtime.NumCPU(). The final result is derived in
+// product-name order, so MostPopularProduct and MaxQuantitySoldUnits agree
+// with AnalyzeData regardless of how records are shuffled across shards.
+// TopN is sorted by revenue, so its order also agrees except where two
+// products' revenues differ only by rounding. Revenue totals are only guaranteed to match up to
+// floating-point rounding: IEEE-754 addition isn't associative, so summing
+// per-shard partial sums in a different order than AnalyzeData's strictly
+// sequential per-record summation can round TotalRevenue and
+// RevenueByProduct differently for fractional prices.
+func AnalyzeDataParallel(records []SaleRecord, workers int) AnalysisResult {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(records) {
+		workers = len(records)
+	}
+	if workers <= 1 {
+		return AnalyzeData(records)
+	}
+
+	shardSize := (len(records) + workers - 1) / workers
+	partials := make([]*accumulator, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * shardSize
+		if start >= len(records) {
+			partials[w] = newAccumulator()
+			continue
+		}
+		end := start + shardSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			acc := newAccumulator()
+			for _, record := range records[start:end] {
+				acc.add(record)
+			}
+			partials[w] = acc
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	merged := newAccumulator()
+	for _, partial := range partials {
+		merged.merge(partial)
+	}
+
+	return merged.result(len(records))
+}
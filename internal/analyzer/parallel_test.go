@@ -0,0 +1,142 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"sales-analysis/internal/analyzer"
+)
+
+func syntheticRecords(n int) []analyzer.SaleRecord {
+	products := []string{"Laptop", "Mouse", "Keyboard", "Monitor", "Headset"}
+	records := make([]analyzer.SaleRecord, n)
+	for i := 0; i < n; i++ {
+		records[i] = analyzer.SaleRecord{
+			Date:     fmt.Sprintf("2023-10-%02d", (i%28)+1),
+			Product:  products[i%len(products)],
+			Quantity: (i % 7) + 1,
+			// Kept as small exact integers so summation order never
+			// introduces floating-point rounding differences between the
+			// sequential and parallel paths.
+			Price: float64((i % 50) + 1),
+		}
+	}
+	return records
+}
+
+// TestAnalyzeDataParallel_MatchesSequential verifies that AnalyzeDataParallel
+// produces bit-identical results to AnalyzeData across shuffled inputs and a
+// range of worker counts, when prices are exact integers (summation order
+// can't introduce rounding differences in that case). See
+// TestAnalyzeDataParallel_FractionalPricesAgreeWithinTolerance for the
+// general, fractional-price case, where only approximate agreement holds.
+func TestAnalyzeDataParallel_MatchesSequential(t *testing.T) {
+	records := syntheticRecords(2000)
+	want := analyzer.AnalyzeData(records)
+
+	rng := rand.New(rand.NewSource(42))
+	for _, workers := range []int{0, 1, 2, 3, 8, 64} {
+		shuffled := append([]analyzer.SaleRecord(nil), records...)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		got := analyzer.AnalyzeDataParallel(shuffled, workers)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("AnalyzeDataParallel(workers=%d) = %+v, want %+v", workers, got, want)
+		}
+	}
+}
+
+// TestAnalyzeDataParallel_FractionalPricesAgreeWithinTolerance verifies that,
+// for realistic fractional prices spread across shard boundaries,
+// AnalyzeDataParallel's revenue totals agree with AnalyzeData's only up to
+// floating-point rounding (per-shard partial sums associate differently than
+// AnalyzeData's strictly sequential summation), while the product ranking
+// they're derived from still matches exactly.
+func TestAnalyzeDataParallel_FractionalPricesAgreeWithinTolerance(t *testing.T) {
+	prices := []float64{1200.50, 25.99, 75.33, 999.95, 14.07}
+	products := []string{"Laptop", "Mouse", "Keyboard", "Monitor", "Headset"}
+	records := make([]analyzer.SaleRecord, 5000)
+	for i := range records {
+		records[i] = analyzer.SaleRecord{
+			Date:     fmt.Sprintf("2023-10-%02d", (i%28)+1),
+			Product:  products[i%len(products)],
+			Quantity: (i % 7) + 1,
+			Price:    prices[i%len(prices)],
+		}
+	}
+
+	want := analyzer.AnalyzeData(records)
+
+	const tolerance = 1e-6
+	for _, workers := range []int{2, 3, 8} {
+		got := analyzer.AnalyzeDataParallel(records, workers)
+
+		if math.Abs(got.TotalRevenue-want.TotalRevenue) > tolerance {
+			t.Errorf("workers=%d: TotalRevenue got %v, want %v (within %v)", workers, got.TotalRevenue, want.TotalRevenue, tolerance)
+		}
+		for product, wantRevenue := range want.RevenueByProduct {
+			if gotRevenue := got.RevenueByProduct[product]; math.Abs(gotRevenue-wantRevenue) > tolerance {
+				t.Errorf("workers=%d: RevenueByProduct[%q] got %v, want %v (within %v)", workers, product, gotRevenue, wantRevenue, tolerance)
+			}
+		}
+
+		if got.MostPopularProduct != want.MostPopularProduct {
+			t.Errorf("workers=%d: MostPopularProduct got %q, want %q", workers, got.MostPopularProduct, want.MostPopularProduct)
+		}
+		if len(got.TopN) != len(want.TopN) {
+			t.Fatalf("workers=%d: TopN got %d entries, want %d", workers, len(got.TopN), len(want.TopN))
+		}
+		for i := range want.TopN {
+			if got.TopN[i].Product != want.TopN[i].Product || got.TopN[i].Quantity != want.TopN[i].Quantity {
+				t.Errorf("workers=%d: TopN[%d] got %+v, want %+v", workers, i, got.TopN[i], want.TopN[i])
+			} else if math.Abs(got.TopN[i].Revenue-want.TopN[i].Revenue) > tolerance {
+				t.Errorf("workers=%d: TopN[%d].Revenue got %v, want %v (within %v)", workers, i, got.TopN[i].Revenue, want.TopN[i].Revenue, tolerance)
+			}
+		}
+	}
+}
+
+// TestAnalyzeDataParallel_EmptyInput verifies the parallel path handles an
+// empty slice without dividing by zero or spawning workers.
+func TestAnalyzeDataParallel_EmptyInput(t *testing.T) {
+	got := analyzer.AnalyzeDataParallel(nil, 4)
+	want := analyzer.AnalyzeData(nil)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AnalyzeDataParallel(nil) = %+v, want %+v", got, want)
+	}
+}
+
+// TestAnalyzeData_TopNTieBreak verifies that products tied on revenue are
+// ordered deterministically by name.
+func TestAnalyzeData_TopNTieBreak(t *testing.T) {
+	records := []analyzer.SaleRecord{
+		{Date: "2023", Product: "Zeta", Quantity: 1, Price: 10.0},
+		{Date: "2023", Product: "Alpha", Quantity: 1, Price: 10.0},
+	}
+	result := analyzer.AnalyzeData(records)
+	if len(result.TopN) != 2 {
+		t.Fatalf("TopN got %d entries, want 2", len(result.TopN))
+	}
+	if result.TopN[0].Product != "Alpha" || result.TopN[1].Product != "Zeta" {
+		t.Errorf("TopN tie-break got %+v, want Alpha before Zeta", result.TopN)
+	}
+}
+
+func BenchmarkAnalyzeData(b *testing.B) {
+	records := syntheticRecords(10_000_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzer.AnalyzeData(records)
+	}
+}
+
+func BenchmarkAnalyzeDataParallel(b *testing.B) {
+	records := syntheticRecords(10_000_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzer.AnalyzeDataParallel(records, 0)
+	}
+}
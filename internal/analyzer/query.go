@@ -0,0 +1,469 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// QueryResult is the tabular output of executing a Query against a slice of
+// SaleRecord: one output column per SelectColumn, in order.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Execute runs q against records: filtering by WHERE, grouping by GROUP BY
+// (or implicitly over the whole input when an aggregate is selected without
+// one), evaluating the SELECT list per row or per group, then sorting and
+// truncating per ORDER BY and LIMIT.
+func Execute(records []SaleRecord, q *Query) (QueryResult, error) {
+	filtered := make([]SaleRecord, 0, len(records))
+	for _, record := range records {
+		if q.Where == nil {
+			filtered = append(filtered, record)
+			continue
+		}
+		v, err := evalExpr(q.Where, record)
+		if err != nil {
+			return QueryResult{}, err
+		}
+		if !truthy(v) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	aggregated := len(q.GroupBy) > 0
+	if !aggregated {
+		for _, col := range q.Columns {
+			if containsAggregate(col.Expr) {
+				aggregated = true
+				break
+			}
+		}
+	}
+
+	columns := make([]string, len(q.Columns))
+	for i, col := range q.Columns {
+		columns[i] = col.Alias
+	}
+	result := QueryResult{Columns: columns}
+
+	if !aggregated {
+		for _, record := range filtered {
+			row := make([]any, len(q.Columns))
+			for i, col := range q.Columns {
+				v, err := evalExpr(col.Expr, record)
+				if err != nil {
+					return QueryResult{}, err
+				}
+				row[i] = v
+			}
+			result.Rows = append(result.Rows, row)
+		}
+	} else {
+		groups, order := groupRecords(filtered, q.GroupBy)
+		for _, key := range order {
+			members := groups[key]
+			row := make([]any, len(q.Columns))
+			for i, col := range q.Columns {
+				v, err := evalGroupExpr(col.Expr, members)
+				if err != nil {
+					return QueryResult{}, err
+				}
+				row[i] = v
+			}
+			result.Rows = append(result.Rows, row)
+		}
+	}
+
+	if len(q.OrderBy) > 0 {
+		if err := sortRows(&result, q.OrderBy); err != nil {
+			return QueryResult{}, err
+		}
+	}
+	if q.Limit > 0 && len(result.Rows) > q.Limit {
+		result.Rows = result.Rows[:q.Limit]
+	}
+
+	return result, nil
+}
+
+// groupRecords partitions records by the values of their groupBy fields,
+// returning the groups alongside the order their keys were first seen in
+// (empty groupBy yields a single group holding every record, for queries
+// that aggregate over the whole input).
+func groupRecords(records []SaleRecord, groupBy []string) (map[string][]SaleRecord, []string) {
+	groups := make(map[string][]SaleRecord)
+	var order []string
+	if len(groupBy) == 0 {
+		// A whole-table aggregate always produces exactly one row, even over
+		// zero records (e.g. COUNT(*) should report 0, not be absent).
+		groups[""] = nil
+		order = append(order, "")
+	}
+	for _, record := range records {
+		key := groupKey(record, groupBy)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], record)
+	}
+	return groups, order
+}
+
+func groupKey(record SaleRecord, groupBy []string) string {
+	parts := make([]string, len(groupBy))
+	for i, field := range groupBy {
+		v, _ := evalExpr(Ident{Name: field}, record)
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+// evalGroupExpr evaluates expr over a group of records: aggregate functions
+// fold over every member, while plain expressions (typically a GROUP BY
+// column) are evaluated against the group's first record.
+func evalGroupExpr(expr Expr, records []SaleRecord) (any, error) {
+	switch e := expr.(type) {
+	case FuncCall:
+		return evalAggregate(e, records)
+	case BinaryExpr:
+		left, err := evalGroupExpr(e.Left, records)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evalGroupExpr(e.Right, records)
+		if err != nil {
+			return nil, err
+		}
+		return applyBinary(e.Op, left, right)
+	default:
+		if len(records) == 0 {
+			return nil, nil
+		}
+		return evalExpr(expr, records[0])
+	}
+}
+
+func evalAggregate(fc FuncCall, records []SaleRecord) (any, error) {
+	switch fc.Name {
+	case "COUNT":
+		if fc.Arg == nil {
+			return float64(len(records)), nil
+		}
+		count := 0
+		for _, record := range records {
+			v, err := evalExpr(fc.Arg, record)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				count++
+			}
+		}
+		return float64(count), nil
+
+	case "SUM", "AVG":
+		var sum float64
+		for _, record := range records {
+			n, err := evalNumeric(fc, record)
+			if err != nil {
+				return nil, err
+			}
+			sum += n
+		}
+		if fc.Name == "AVG" {
+			if len(records) == 0 {
+				return 0.0, nil
+			}
+			return sum / float64(len(records)), nil
+		}
+		return sum, nil
+
+	case "MIN", "MAX":
+		var best float64
+		for i, record := range records {
+			n, err := evalNumeric(fc, record)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 || (fc.Name == "MIN" && n < best) || (fc.Name == "MAX" && n > best) {
+				best = n
+			}
+		}
+		return best, nil
+
+	default:
+		return nil, fmt.Errorf("query: unknown aggregate function %q", fc.Name)
+	}
+}
+
+func evalNumeric(fc FuncCall, record SaleRecord) (float64, error) {
+	v, err := evalExpr(fc.Arg, record)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("query: %s requires a numeric argument", fc.Name)
+	}
+	return n, nil
+}
+
+// evalExpr evaluates a non-aggregate expression against a single record,
+// returning a float64, string, or bool.
+func evalExpr(expr Expr, record SaleRecord) (any, error) {
+	switch e := expr.(type) {
+	case NumberLit:
+		return e.Value, nil
+	case StringLit:
+		return e.Value, nil
+	case Ident:
+		switch strings.ToLower(e.Name) {
+		case "date":
+			return record.Date, nil
+		case "product":
+			return record.Product, nil
+		case "quantity":
+			return float64(record.Quantity), nil
+		case "price":
+			return record.Price, nil
+		default:
+			return nil, fmt.Errorf("query: unknown field %q", e.Name)
+		}
+	case BinaryExpr:
+		left, err := evalExpr(e.Left, record)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evalExpr(e.Right, record)
+		if err != nil {
+			return nil, err
+		}
+		return applyBinary(e.Op, left, right)
+	case FuncCall:
+		return nil, fmt.Errorf("query: aggregate function %s is not allowed outside an aggregated query", e.Name)
+	default:
+		return nil, fmt.Errorf("query: unsupported expression %s", expr)
+	}
+}
+
+func applyBinary(op string, left, right any) (any, error) {
+	switch op {
+	case "+", "-", "*", "/":
+		l, lok := left.(float64)
+		r, rok := right.(float64)
+		if !lok || !rok {
+			return nil, fmt.Errorf("query: operator %q requires numeric operands", op)
+		}
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		default: // "/"
+			if r == 0 {
+				return nil, fmt.Errorf("query: division by zero")
+			}
+			return l / r, nil
+		}
+
+	case "=", "!=", "<", "<=", ">", ">=":
+		return compareValuesForOp(op, left, right)
+
+	case "AND", "OR":
+		l, lok := left.(bool)
+		r, rok := right.(bool)
+		if !lok || !rok {
+			return nil, fmt.Errorf("query: operator %s requires boolean operands", op)
+		}
+		if op == "AND" {
+			return l && r, nil
+		}
+		return l || r, nil
+
+	default:
+		return nil, fmt.Errorf("query: unknown operator %q", op)
+	}
+}
+
+func compareValuesForOp(op string, left, right any) (any, error) {
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("query: cannot compare %v and %v", left, right)
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("query: cannot compare %v and %v", left, right)
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("query: cannot compare %v and %v", left, right)
+	}
+
+	switch op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	default:
+		return nil, fmt.Errorf("query: unknown comparison operator %q", op)
+	}
+}
+
+func truthy(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func containsAggregate(expr Expr) bool {
+	switch e := expr.(type) {
+	case FuncCall:
+		return true
+	case BinaryExpr:
+		return containsAggregate(e.Left) || containsAggregate(e.Right)
+	default:
+		return false
+	}
+}
+
+func sortRows(result *QueryResult, orderBy []OrderTerm) error {
+	indices := make([]int, len(orderBy))
+	for i, term := range orderBy {
+		idx := indexOfColumn(result.Columns, term.Name)
+		if idx < 0 {
+			return fmt.Errorf("query: ORDER BY references unknown column %q", term.Name)
+		}
+		indices[i] = idx
+	}
+
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		for k, idx := range indices {
+			cmp := compareValues(result.Rows[i][idx], result.Rows[j][idx])
+			if cmp == 0 {
+				continue
+			}
+			if orderBy[k].Desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	return nil
+}
+
+func compareValues(a, b any) int {
+	if af, ok := a.(float64); ok {
+		if bf, ok := b.(float64); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func indexOfColumn(columns []string, name string) int {
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// WriteQueryResultText renders result as a tab-aligned text table.
+func WriteQueryResultText(w io.Writer, result QueryResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(result.Columns, "\t"))
+	for _, row := range result.Rows {
+		fmt.Fprintln(tw, strings.Join(formatRow(row), "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteQueryResultCSV renders result as CSV, with result.Columns as the header.
+func WriteQueryResultCSV(w io.Writer, result QueryResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(result.Columns); err != nil {
+		return err
+	}
+	for _, row := range result.Rows {
+		if err := writer.Write(formatRow(row)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteQueryResultJSON renders result as a JSON array of objects keyed by
+// result.Columns.
+func WriteQueryResultJSON(w io.Writer, result QueryResult) error {
+	rows := make([]map[string]any, len(result.Rows))
+	for i, row := range result.Rows {
+		m := make(map[string]any, len(result.Columns))
+		for j, col := range result.Columns {
+			m[col] = row[j]
+		}
+		rows[i] = m
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func formatRow(row []any) []string {
+	cells := make([]string, len(row))
+	for i, v := range row {
+		cells[i] = formatValue(v)
+	}
+	return cells
+}
+
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind identifies the lexical category of a query token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp // operators and punctuation: = != < <= > >= + - * / , ( )
+)
+
+// token is a single lexical unit produced by the query lexer, tagged with
+// its source position so parse errors can point back at the offending text.
+type token struct {
+	kind tokenKind
+	text string
+	line int
+	col  int
+}
+
+// queryLexer tokenizes a query string for the recursive-descent parser.
+type queryLexer struct {
+	src  []rune
+	pos  int
+	line int
+	col  int
+}
+
+func newQueryLexer(src string) *queryLexer {
+	return &queryLexer{src: []rune(src), line: 1, col: 1}
+}
+
+func (l *queryLexer) peekRune() (rune, bool) {
+	if l.pos >= len(l.src) {
+		return 0, false
+	}
+	return l.src[l.pos], true
+}
+
+func (l *queryLexer) advance() (rune, bool) {
+	r, ok := l.peekRune()
+	if !ok {
+		return 0, false
+	}
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r, true
+}
+
+// next returns the next token, or a tokEOF token once the input is exhausted.
+func (l *queryLexer) next() (token, error) {
+	l.skipSpace()
+
+	line, col := l.line, l.col
+	r, ok := l.peekRune()
+	if !ok {
+		return token{kind: tokEOF, line: line, col: col}, nil
+	}
+
+	switch {
+	case unicode.IsLetter(r) || r == '_':
+		return l.lexIdent(line, col), nil
+	case unicode.IsDigit(r):
+		return l.lexNumber(line, col), nil
+	case r == '\'' || r == '"':
+		return l.lexString(line, col)
+	default:
+		return l.lexOperator(line, col)
+	}
+}
+
+func (l *queryLexer) skipSpace() {
+	for {
+		r, ok := l.peekRune()
+		if !ok || !unicode.IsSpace(r) {
+			return
+		}
+		l.advance()
+	}
+}
+
+func (l *queryLexer) lexIdent(line, col int) token {
+	var sb strings.Builder
+	for {
+		r, ok := l.peekRune()
+		if !ok || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			break
+		}
+		sb.WriteRune(r)
+		l.advance()
+	}
+	return token{kind: tokIdent, text: sb.String(), line: line, col: col}
+}
+
+func (l *queryLexer) lexNumber(line, col int) token {
+	var sb strings.Builder
+	seenDot := false
+	for {
+		r, ok := l.peekRune()
+		if !ok {
+			break
+		}
+		if r == '.' && !seenDot {
+			seenDot = true
+		} else if !unicode.IsDigit(r) {
+			break
+		}
+		sb.WriteRune(r)
+		l.advance()
+	}
+	return token{kind: tokNumber, text: sb.String(), line: line, col: col}
+}
+
+func (l *queryLexer) lexString(line, col int) (token, error) {
+	quote, _ := l.advance()
+	var sb strings.Builder
+	for {
+		r, ok := l.advance()
+		if !ok {
+			return token{}, &ParseError{Line: line, Col: col, Msg: "unterminated string literal"}
+		}
+		if r == quote {
+			return token{kind: tokString, text: sb.String(), line: line, col: col}, nil
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func (l *queryLexer) lexOperator(line, col int) (token, error) {
+	r, _ := l.advance()
+	two := func(second rune, text string) (token, bool) {
+		if n, ok := l.peekRune(); ok && n == second {
+			l.advance()
+			return token{kind: tokOp, text: text, line: line, col: col}, true
+		}
+		return token{}, false
+	}
+
+	switch r {
+	case '!':
+		if t, ok := two('=', "!="); ok {
+			return t, nil
+		}
+		return token{}, &ParseError{Line: line, Col: col, Msg: "unexpected character '!'"}
+	case '<':
+		if t, ok := two('=', "<="); ok {
+			return t, nil
+		}
+		return token{kind: tokOp, text: "<", line: line, col: col}, nil
+	case '>':
+		if t, ok := two('=', ">="); ok {
+			return t, nil
+		}
+		return token{kind: tokOp, text: ">", line: line, col: col}, nil
+	case '=', '+', '-', '*', '/', ',', '(', ')':
+		return token{kind: tokOp, text: string(r), line: line, col: col}, nil
+	default:
+		return token{}, &ParseError{Line: line, Col: col, Msg: fmt.Sprintf("unexpected character %q", r)}
+	}
+}
+
+// ParseError describes a syntax error in a query string, with the 1-indexed
+// line and column at which it was detected.
+type ParseError struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("query: line %d, col %d: %s", e.Line, e.Col, e.Msg)
+}
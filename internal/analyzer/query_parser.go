@@ -0,0 +1,455 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is a node in a parsed query's expression tree.
+type Expr interface {
+	String() string
+}
+
+// Ident references a SaleRecord field by name (Date, Product, Quantity, Price).
+type Ident struct{ Name string }
+
+func (e Ident) String() string { return e.Name }
+
+// NumberLit is a numeric literal.
+type NumberLit struct{ Value float64 }
+
+func (e NumberLit) String() string { return strconv.FormatFloat(e.Value, 'g', -1, 64) }
+
+// StringLit is a quoted string literal.
+type StringLit struct{ Value string }
+
+func (e StringLit) String() string { return strconv.Quote(e.Value) }
+
+// BinaryExpr is an arithmetic, comparison, or logical operation.
+type BinaryExpr struct {
+	Op          string
+	Left, Right Expr
+}
+
+func (e BinaryExpr) String() string { return fmt.Sprintf("(%s %s %s)", e.Left, e.Op, e.Right) }
+
+// FuncCall is an aggregate function applied to Arg, e.g. SUM(quantity*price).
+// Arg is nil for COUNT(*).
+type FuncCall struct {
+	Name string
+	Arg  Expr
+}
+
+func (e FuncCall) String() string {
+	if e.Arg == nil {
+		return e.Name + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, e.Arg)
+}
+
+// aggregateFuncs is the set of recognized aggregate function names.
+var aggregateFuncs = map[string]bool{"SUM": true, "COUNT": true, "AVG": true, "MIN": true, "MAX": true}
+
+// SelectColumn is one entry in a query's SELECT list.
+type SelectColumn struct {
+	Expr  Expr
+	Alias string // output column name: the AS alias, or Expr.String() if none was given
+}
+
+// OrderTerm is one entry in a query's ORDER BY list.
+type OrderTerm struct {
+	Name string // references a SelectColumn's Alias
+	Desc bool
+}
+
+// Query is the parsed form of a SELECT ... WHERE ... GROUP BY ... ORDER BY ... LIMIT ... string.
+type Query struct {
+	Columns []SelectColumn
+	Where   Expr // nil if no WHERE clause
+	GroupBy []string
+	OrderBy []OrderTerm
+	Limit   int // 0 means no limit
+}
+
+// queryParser is a recursive-descent parser over the token stream produced
+// by queryLexer.
+type queryParser struct {
+	lex *queryLexer
+	cur token
+}
+
+// ParseQuery parses a SQL-like query string into a Query. Supported grammar:
+//
+//	query       := "SELECT" selectList whereClause? groupByClause? orderByClause? limitClause?
+//	selectList  := selectItem ("," selectItem)*
+//	selectItem  := expr ("AS" ident)?
+//	whereClause := "WHERE" expr
+//	groupBy     := "GROUP" "BY" ident ("," ident)*
+//	orderBy     := "ORDER" "BY" orderItem ("," orderItem)*
+//	orderItem   := ident ("ASC" | "DESC")?
+//	limitClause := "LIMIT" number
+//	expr        := comparison (("AND" | "OR") comparison)*
+//	comparison  := additive (("=" | "!=" | "<" | "<=" | ">" | ">=") additive)?
+//	additive    := multiplicative (("+" | "-") multiplicative)*
+//	multiplicative := primary (("*" | "/") primary)*
+//	primary     := NUMBER | STRING | ident | ident "(" ("*" | expr) ")" | "(" expr ")"
+func ParseQuery(src string) (*Query, error) {
+	p := &queryParser{lex: newQueryLexer(src)}
+	if err := p.nextToken(); err != nil {
+		return nil, err
+	}
+	return p.parseQuery()
+}
+
+func (p *queryParser) nextToken() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return &ParseError{Line: p.cur.line, Col: p.cur.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isKeyword reports whether the current token is the identifier kw, matched
+// case-insensitively as SQL keywords conventionally are.
+func (p *queryParser) isKeyword(kw string) bool {
+	return p.cur.kind == tokIdent && strings.EqualFold(p.cur.text, kw)
+}
+
+func (p *queryParser) expectKeyword(kw string) error {
+	if !p.isKeyword(kw) {
+		return p.errorf("expected %q", kw)
+	}
+	return p.nextToken()
+}
+
+func (p *queryParser) expectOp(op string) error {
+	if p.cur.kind != tokOp || p.cur.text != op {
+		return p.errorf("expected %q", op)
+	}
+	return p.nextToken()
+}
+
+func (p *queryParser) parseQuery() (*Query, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	cols, err := p.parseSelectList()
+	if err != nil {
+		return nil, err
+	}
+	q.Columns = cols
+
+	if p.isKeyword("WHERE") {
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		q.Where = where
+	}
+
+	if p.isKeyword("GROUP") {
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		names, err := p.parseIdentList()
+		if err != nil {
+			return nil, err
+		}
+		q.GroupBy = names
+	}
+
+	if p.isKeyword("ORDER") {
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		terms, err := p.parseOrderByList()
+		if err != nil {
+			return nil, err
+		}
+		q.OrderBy = terms
+	}
+
+	if p.isKeyword("LIMIT") {
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokNumber {
+			return nil, p.errorf("expected a number after LIMIT")
+		}
+		n, err := strconv.Atoi(p.cur.text)
+		if err != nil {
+			return nil, p.errorf("invalid LIMIT value %q", p.cur.text)
+		}
+		q.Limit = n
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.cur.kind != tokEOF {
+		return nil, p.errorf("unexpected trailing input %q", p.cur.text)
+	}
+
+	return q, nil
+}
+
+func (p *queryParser) parseSelectList() ([]SelectColumn, error) {
+	var cols []SelectColumn
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		alias := expr.String()
+		if p.isKeyword("AS") {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+			if p.cur.kind != tokIdent {
+				return nil, p.errorf("expected an identifier after AS")
+			}
+			alias = p.cur.text
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+		} else if ident, ok := expr.(Ident); ok {
+			alias = ident.Name
+		}
+		cols = append(cols, SelectColumn{Expr: expr, Alias: alias})
+
+		if p.cur.kind == tokOp && p.cur.text == "," {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	return cols, nil
+}
+
+func (p *queryParser) parseIdentList() ([]string, error) {
+	var names []string
+	for {
+		if p.cur.kind != tokIdent {
+			return nil, p.errorf("expected an identifier")
+		}
+		names = append(names, p.cur.text)
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind == tokOp && p.cur.text == "," {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	return names, nil
+}
+
+func (p *queryParser) parseOrderByList() ([]OrderTerm, error) {
+	var terms []OrderTerm
+	for {
+		if p.cur.kind != tokIdent {
+			return nil, p.errorf("expected an identifier in ORDER BY")
+		}
+		term := OrderTerm{Name: p.cur.text}
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("ASC") {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+		} else if p.isKeyword("DESC") {
+			term.Desc = true
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+		}
+		terms = append(terms, term)
+
+		if p.cur.kind == tokOp && p.cur.text == "," {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	return terms, nil
+}
+
+// parseExpr parses logical AND/OR over comparisons.
+func (p *queryParser) parseExpr() (Expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") || p.isKeyword("OR") {
+		op := strings.ToUpper(p.cur.text)
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+var comparisonOps = map[string]bool{"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *queryParser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if p.cur.kind == tokOp && comparisonOps[p.cur.text] {
+		op := p.cur.text
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return BinaryExpr{Op: op, Left: left, Right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOp && (p.cur.text == "+" || p.cur.text == "-") {
+		op := p.cur.text
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseMultiplicative() (Expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOp && (p.cur.text == "*" || p.cur.text == "/") {
+		op := p.cur.text
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parsePrimary() (Expr, error) {
+	switch p.cur.kind {
+	case tokNumber:
+		value, err := strconv.ParseFloat(p.cur.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.cur.text)
+		}
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		return NumberLit{Value: value}, nil
+
+	case tokString:
+		value := p.cur.text
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		return StringLit{Value: value}, nil
+
+	case tokIdent:
+		name := p.cur.text
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind == tokOp && p.cur.text == "(" {
+			return p.parseFuncCall(name)
+		}
+		return Ident{Name: name}, nil
+
+	case tokOp:
+		if p.cur.text == "(" {
+			if err := p.nextToken(); err != nil {
+				return nil, err
+			}
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.errorf("unexpected token %q", p.cur.text)
+}
+
+func (p *queryParser) parseFuncCall(name string) (Expr, error) {
+	upperName := strings.ToUpper(name)
+	if !aggregateFuncs[upperName] {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+
+	if upperName == "COUNT" && p.cur.kind == tokOp && p.cur.text == "*" {
+		if err := p.nextToken(); err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return FuncCall{Name: upperName}, nil
+	}
+
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	return FuncCall{Name: upperName, Arg: arg}, nil
+}
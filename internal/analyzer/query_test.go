@@ -0,0 +1,135 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"sales-analysis/internal/analyzer"
+)
+
+func queryFixture() []analyzer.SaleRecord {
+	return []analyzer.SaleRecord{
+		{Date: "2023-10-01", Product: "Laptop", Quantity: 2, Price: 1200.00},
+		{Date: "2023-10-02", Product: "Mouse", Quantity: 10, Price: 25.00},
+		{Date: "2023-10-03", Product: "Laptop", Quantity: 1, Price: 1200.00},
+		{Date: "2023-10-04", Product: "Keyboard", Quantity: 5, Price: 75.00},
+	}
+}
+
+// TestExecute_WherePredicate verifies that a WHERE clause filters rows before
+// the SELECT list is evaluated.
+func TestExecute_WherePredicate(t *testing.T) {
+	query, err := analyzer.ParseQuery(`SELECT product WHERE quantity > 4`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	result, err := analyzer.Execute(queryFixture(), query)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"Mouse", "Keyboard"}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("Execute() got %d rows, want %d", len(result.Rows), len(want))
+	}
+	for i, product := range want {
+		if result.Rows[i][0] != product {
+			t.Errorf("row %d got %v, want %s", i, result.Rows[i][0], product)
+		}
+	}
+}
+
+// TestExecute_AggregateMatchesAnalyzeData verifies that a query aggregating
+// the whole table agrees with AnalyzeData's totals.
+func TestExecute_AggregateMatchesAnalyzeData(t *testing.T) {
+	records := queryFixture()
+	want := analyzer.AnalyzeData(records)
+
+	query, err := analyzer.ParseQuery(`SELECT SUM(quantity*price) AS revenue, COUNT(*) AS n`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	result, err := analyzer.Execute(records, query)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Rows) != 1 {
+		t.Fatalf("Execute() got %d rows, want 1", len(result.Rows))
+	}
+	if got := result.Rows[0][0]; got != want.TotalRevenue {
+		t.Errorf("revenue got %v, want %v", got, want.TotalRevenue)
+	}
+	if got := result.Rows[0][1]; got != float64(want.TotalTransactions) {
+		t.Errorf("count got %v, want %v", got, want.TotalTransactions)
+	}
+}
+
+// TestExecute_GroupByOrderByLimit verifies grouping, aggregate folding per
+// group, descending ORDER BY on an aggregate alias, and LIMIT truncation.
+func TestExecute_GroupByOrderByLimit(t *testing.T) {
+	query, err := analyzer.ParseQuery(
+		`SELECT product, SUM(quantity*price) AS revenue GROUP BY product ORDER BY revenue DESC LIMIT 1`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	result, err := analyzer.Execute(queryFixture(), query)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(result.Rows) != 1 {
+		t.Fatalf("Execute() got %d rows, want 1", len(result.Rows))
+	}
+	if result.Rows[0][0] != "Laptop" {
+		t.Errorf("top product got %v, want Laptop", result.Rows[0][0])
+	}
+	if result.Rows[0][1] != 3600.0 {
+		t.Errorf("top revenue got %v, want 3600", result.Rows[0][1])
+	}
+}
+
+// TestExecute_AggregateOverEmptyResult verifies that a whole-table aggregate
+// still produces exactly one row, with zero-valued aggregates, when no
+// record survives the WHERE clause.
+func TestExecute_AggregateOverEmptyResult(t *testing.T) {
+	query, err := analyzer.ParseQuery(`SELECT SUM(price) AS total, COUNT(*) AS n WHERE quantity > 1000`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	result, err := analyzer.Execute(queryFixture(), query)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(result.Rows) != 1 {
+		t.Fatalf("Execute() got %d rows, want 1", len(result.Rows))
+	}
+	if result.Rows[0][0] != 0.0 {
+		t.Errorf("total got %v, want 0", result.Rows[0][0])
+	}
+	if result.Rows[0][1] != 0.0 {
+		t.Errorf("n got %v, want 0", result.Rows[0][1])
+	}
+}
+
+// TestParseQuery_SyntaxError verifies that a malformed query reports the
+// line and column of the offending token.
+func TestParseQuery_SyntaxError(t *testing.T) {
+	_, err := analyzer.ParseQuery("SELECT product WHERE")
+	if err == nil {
+		t.Fatal("ParseQuery() error = nil, want a parse error")
+	}
+	parseErr, ok := err.(*analyzer.ParseError)
+	if !ok {
+		t.Fatalf("ParseQuery() error type = %T, want *analyzer.ParseError", err)
+	}
+	if parseErr.Line != 1 {
+		t.Errorf("ParseError.Line got %d, want 1", parseErr.Line)
+	}
+	if parseErr.Col == 0 {
+		t.Error("ParseError.Col got 0, want the column of the unexpected EOF")
+	}
+}
@@ -0,0 +1,304 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// ErrStopIteration can be returned by an Each callback to stop iterating
+// early without treating it as a failure. Each returns nil when the
+// callback returns this sentinel.
+var ErrStopIteration = errors.New("analyzer: stop iteration")
+
+// RowError describes a single row that Reader.Each could not turn into a
+// SaleRecord, e.g. because a field failed to parse.
+type RowError struct {
+	Line  int      // 1-indexed line number in the source, header included
+	Row   []string // the raw row that failed validation
+	Field string   // the field that failed ("fields", "Quantity", "Price", or "Date")
+	Err   error    // the underlying error
+}
+
+// Error implements the error interface so a RowError can be returned
+// directly from an ErrorHandler or wrapped with fmt.Errorf.
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d skipped - %s: %v", e.Line, e.Field, e.Err)
+}
+
+// Unwrap exposes the underlying error for errors.Is/errors.As.
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// ParseOptions configures the CSV dialect, column layout, and streaming
+// bounds used by a Reader (and, through it, ParseCSVWithOptions).
+type ParseOptions struct {
+	// From is the first data row (1-indexed, header excluded) to emit; 0 means start at the first row.
+	From int
+	// To is the last data row (1-indexed, inclusive) to emit; 0 means no upper bound.
+	To int
+	// Limit caps the number of rows emitted; 0 means unlimited.
+	Limit int
+
+	// Comma is the field delimiter; the zero value defaults to ','.
+	Comma rune
+	// Comment, if set, marks lines beginning with this rune as comments to be ignored.
+	Comment rune
+	// LazyQuotes relaxes quote parsing to tolerate malformed quotes.
+	LazyQuotes bool
+	// TrimLeadingSpace trims leading whitespace from fields.
+	TrimLeadingSpace bool
+	// Encoding names the source text encoding: "" or "utf-8" (default), "gbk", or "latin1".
+	Encoding string
+	// SkipRows is the number of additional data rows to discard right after the header.
+	SkipRows int
+	// HeaderMap remaps source column names to the required fields
+	// ("Date", "Product", "Quantity", "Price"). If nil, the header is
+	// assumed to already provide exactly those four columns in that order.
+	HeaderMap map[string]string
+	// DateLayout, if set, is used to additionally parse Date into ParsedDate
+	// via time.Parse. Rows that fail to parse are skipped with a warning.
+	DateLayout string
+
+	// ErrorHandler, if set, is invoked with a RowError for every row that
+	// fails validation instead of printing a "Warning: ..." line to stdout.
+	// Returning nil skips the row and continues; returning a non-nil error
+	// aborts Each, which returns that error.
+	ErrorHandler func(RowError) error
+}
+
+// Reader streams SaleRecord values from a CSV source one row at a time,
+// without materializing the whole file in memory.
+type Reader struct {
+	src  io.Reader
+	opts ParseOptions
+}
+
+// NewReader returns a Reader that parses CSV data from src, configured by opts.
+func NewReader(src io.Reader, opts ParseOptions) *Reader {
+	return &Reader{src: src, opts: opts}
+}
+
+// decoderFor returns the transform.Transformer for the named encoding, or nil
+// if the source is already UTF-8 and needs no conversion.
+func decoderFor(name string) (transform.Transformer, error) {
+	switch name {
+	case "", "utf-8", "utf8":
+		return nil, nil
+	case "gbk":
+		return simplifiedchinese.GBK.NewDecoder(), nil
+	case "latin1", "iso-8859-1":
+		return charmap.ISO8859_1.NewDecoder(), nil
+	default:
+		return nil, fmt.Errorf("analyzer: unsupported encoding %q", name)
+	}
+}
+
+// Each reads the header, then parses and validates each data row, invoking
+// fn for every valid SaleRecord in order. Rows with the wrong number of
+// fields or unparsable Quantity/Price/Date are skipped with a warning,
+// matching ParseCSV's historical behavior. If fn returns ErrStopIteration,
+// Each stops reading and returns nil; any other error from fn aborts
+// iteration and is returned as-is.
+func (r *Reader) Each(fn func(SaleRecord) error) error {
+	src := r.src
+	if dec, err := decoderFor(r.opts.Encoding); err != nil {
+		return err
+	} else if dec != nil {
+		src = transform.NewReader(src, dec)
+	}
+
+	reader := csv.NewReader(src)
+	// Setting FieldsPerRecord to -1 allows reading records with a variable number of fields.
+	// This lets our code handle missing/extra fields gracefully instead of relying on a critical CSV error.
+	reader.FieldsPerRecord = -1
+	if r.opts.Comma != 0 {
+		reader.Comma = r.opts.Comma
+	}
+	reader.Comment = r.opts.Comment
+	reader.LazyQuotes = r.opts.LazyQuotes
+	reader.TrimLeadingSpace = r.opts.TrimLeadingSpace
+
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	// dateIdx, productIdx, quantityIdx, priceIdx locate the required columns
+	// within a row. They default to the original fixed layout, and are only
+	// recomputed from the header when HeaderMap remaps arbitrary column names.
+	dateIdx, productIdx, quantityIdx, priceIdx := 0, 1, 2, 3
+	if r.opts.HeaderMap != nil {
+		dateIdx, productIdx, quantityIdx, priceIdx, err = resolveColumns(header, r.opts.HeaderMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < r.opts.SkipRows; i++ {
+		if _, err := reader.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error skipping row: %w", err)
+		}
+	}
+
+	rowNumber := 0 // 1-indexed data row, not counting the header or skipped rows
+	emitted := 0
+	for lineNumber := 2 + r.opts.SkipRows; ; lineNumber++ { // Start after the header and any skipped rows
+		row, err := reader.Read()
+		if err == io.EOF {
+			break // End of file reached
+		}
+		if err != nil {
+			// Handle critical I/O errors that are not EOF
+			return fmt.Errorf("critical error reading line %d: %w", lineNumber, err)
+		}
+		// Report the row's actual source line, which differs from a simple
+		// count when comment lines, blank lines, or multi-line fields occur.
+		lineNumber, _ = reader.FieldPos(0)
+
+		rowNumber++
+		if r.opts.From > 0 && rowNumber < r.opts.From {
+			continue
+		}
+		if r.opts.To > 0 && rowNumber > r.opts.To {
+			break
+		}
+
+		requiredCols := maxInt(maxInt(dateIdx, productIdx), maxInt(quantityIdx, priceIdx)) + 1
+		if r.opts.HeaderMap == nil {
+			// Preserve the original, stricter check when no remapping is in play.
+			if len(row) != 4 {
+				err := r.handleRowError(RowError{Line: lineNumber, Row: row, Field: "fields",
+					Err: fmt.Errorf("incorrect number of fields (%d instead of 4)", len(row))})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		} else if len(row) < requiredCols {
+			err := r.handleRowError(RowError{Line: lineNumber, Row: row, Field: "fields",
+				Err: fmt.Errorf("incorrect number of fields (%d, need at least %d)", len(row), requiredCols)})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Parse Quantity (string to int)
+		quantity, err := strconv.Atoi(row[quantityIdx])
+		if err != nil {
+			if err := r.handleRowError(RowError{Line: lineNumber, Row: row, Field: "Quantity", Err: err}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Parse Price (string to float64)
+		// Note: strconv.ParseFloat handles "NaN" and "Inf" without error.
+		// We only skip on true parsing errors (e.g., "invalid_price").
+		price, err := strconv.ParseFloat(row[priceIdx], 64)
+		if err != nil {
+			if err := r.handleRowError(RowError{Line: lineNumber, Row: row, Field: "Price", Err: err}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record := SaleRecord{
+			Date:     row[dateIdx],
+			Product:  row[productIdx],
+			Quantity: quantity,
+			Price:    price,
+		}
+
+		if r.opts.DateLayout != "" {
+			parsed, err := time.Parse(r.opts.DateLayout, record.Date)
+			if err != nil {
+				if err := r.handleRowError(RowError{Line: lineNumber, Row: row, Field: "Date", Err: err}); err != nil {
+					return err
+				}
+				continue
+			}
+			record.ParsedDate = parsed
+		}
+
+		if err := fn(record); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+
+		emitted++
+		if r.opts.Limit > 0 && emitted >= r.opts.Limit {
+			break
+		}
+	}
+
+	return nil
+}
+
+// handleRowError reports a malformed row. If an ErrorHandler is configured,
+// it decides whether parsing continues (return nil) or aborts (return a
+// non-nil error). Otherwise the row is reported as a "Warning: ..." line on
+// stdout and skipped, matching ParseCSV's historical behavior.
+func (r *Reader) handleRowError(rowErr RowError) error {
+	if r.opts.ErrorHandler != nil {
+		return r.opts.ErrorHandler(rowErr)
+	}
+	fmt.Printf("Warning: %s\n", rowErr)
+	return nil
+}
+
+// resolveColumns locates the Date/Product/Quantity/Price columns within
+// header, translating source column names through headerMap first.
+func resolveColumns(header []string, headerMap map[string]string) (dateIdx, productIdx, quantityIdx, priceIdx int, err error) {
+	indices := map[string]int{}
+	for i, name := range header {
+		canonical := name
+		if mapped, ok := headerMap[name]; ok {
+			canonical = mapped
+		}
+		indices[canonical] = i
+	}
+
+	lookup := func(field string) (int, error) {
+		idx, ok := indices[field]
+		if !ok {
+			return 0, fmt.Errorf("analyzer: required column %q not found in header %v", field, header)
+		}
+		return idx, nil
+	}
+
+	if dateIdx, err = lookup("Date"); err != nil {
+		return
+	}
+	if productIdx, err = lookup("Product"); err != nil {
+		return
+	}
+	if quantityIdx, err = lookup("Quantity"); err != nil {
+		return
+	}
+	if priceIdx, err = lookup("Price"); err != nil {
+		return
+	}
+	return
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
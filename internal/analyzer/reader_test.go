@@ -0,0 +1,139 @@
+package analyzer_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sales-analysis/internal/analyzer"
+)
+
+const streamHeader = "Date,Product,Quantity,Price\n"
+
+// TestReaderEach_FromTo verifies that From/To bound which data rows are
+// emitted to the callback, using 1-indexed, inclusive row numbers.
+func TestReaderEach_FromTo(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Row1,1,10.0\n" +
+		"2023-10-02,Row2,2,10.0\n" +
+		"2023-10-03,Row3,3,10.0\n" +
+		"2023-10-04,Row4,4,10.0\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{From: 2, To: 3})
+
+	var products []string
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		products = append(products, record.Product)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+
+	want := []string{"Row2", "Row3"}
+	if len(products) != len(want) {
+		t.Fatalf("Each() emitted %v, want %v", products, want)
+	}
+	for i := range want {
+		if products[i] != want[i] {
+			t.Errorf("products[%d] = %s, want %s", i, products[i], want[i])
+		}
+	}
+}
+
+// TestReaderEach_Limit verifies that Limit caps the number of rows emitted.
+func TestReaderEach_Limit(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Row1,1,10.0\n" +
+		"2023-10-02,Row2,2,10.0\n" +
+		"2023-10-03,Row3,3,10.0\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{Limit: 2})
+
+	count := 0
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Each() emitted %d rows, want 2", count)
+	}
+}
+
+// TestReaderEach_StopIteration verifies that returning ErrStopIteration from
+// the callback halts iteration early without surfacing as a failure.
+func TestReaderEach_StopIteration(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Row1,1,10.0\n" +
+		"2023-10-02,Row2,2,10.0\n" +
+		"2023-10-03,Row3,3,10.0\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{})
+
+	var seen []string
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		seen = append(seen, record.Product)
+		if record.Product == "Row2" {
+			return analyzer.ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each() error = %v, want nil", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("Each() visited %v, want it to stop after Row2", seen)
+	}
+}
+
+// TestReaderEach_CallbackError verifies that a non-sentinel error from the
+// callback aborts iteration and is returned to the caller.
+func TestReaderEach_CallbackError(t *testing.T) {
+	csvContent := streamHeader + "2023-10-01,Row1,1,10.0\n"
+	wantErr := errors.New("boom")
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{})
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Each() error = %v, want %v", err, wantErr)
+	}
+}
+
+// TestStreamingMatchesBatch verifies that AnalyzeStream fed from Reader.Each
+// produces the same result as AnalyzeData fed from ParseCSV's in-memory
+// slice, on the same input.
+func TestStreamingMatchesBatch(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Laptop,2,1200.50\n" +
+		"2023-10-02,Mouse,10,25.99\n" +
+		"2023-10-03,Laptop,5,1200.50\n" +
+		"2023-10-04,Keyboard,Two,45.00\n" // malformed, should be skipped in both modes
+
+	batchFile := createTestFile(t, "batch.csv", csvContent)
+	batchRecords, err := analyzer.ParseCSV(batchFile)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	batchResult := analyzer.AnalyzeData(batchRecords)
+
+	ch := make(chan analyzer.SaleRecord)
+	streamReader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{})
+	go func() {
+		defer close(ch)
+		_ = streamReader.Each(func(record analyzer.SaleRecord) error {
+			ch <- record
+			return nil
+		})
+	}()
+	streamResult := analyzer.AnalyzeStream(ch)
+
+	if !reflect.DeepEqual(streamResult, batchResult) {
+		t.Errorf("AnalyzeStream() = %+v, want %+v (batch)", streamResult, batchResult)
+	}
+}
@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteRejectsCSV writes rowErrors to w as a CSV file, one row per rejected
+// input line, so they can be inspected or reprocessed separately from the
+// records that parsed successfully.
+func WriteRejectsCSV(w io.Writer, rowErrors []RowError) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"line", "field", "error", "row"}); err != nil {
+		return err
+	}
+
+	for _, rowErr := range rowErrors {
+		record := []string{
+			strconv.Itoa(rowErr.Line),
+			rowErr.Field,
+			rowErr.Err.Error(),
+			strings.Join(rowErr.Row, ","),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,126 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"sales-analysis/internal/analyzer"
+)
+
+// TestReaderEach_ErrorHandlerCollectsEveryRow verifies that every malformed
+// row is reported to the ErrorHandler exactly once, with its line and field.
+func TestReaderEach_ErrorHandlerCollectsEveryRow(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Laptop,2,1200.50\n" + // valid
+		"2023-10-02,Mouse,Two,25.99\n" + // bad Quantity, line 3
+		"2023-10-03,Keyboard,5,BAD_PRICE\n" + // bad Price, line 4
+		"2023-10-04,Extra,5,10.0,EXTRA\n" // wrong column count, line 5
+
+	var rowErrors []analyzer.RowError
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{
+		ErrorHandler: func(rowErr analyzer.RowError) error {
+			rowErrors = append(rowErrors, rowErr)
+			return nil
+		},
+	})
+
+	var records []analyzer.SaleRecord
+	if err := reader.Each(func(record analyzer.SaleRecord) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		t.Fatalf("Each() error = %v", err)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("Each() kept %d valid records, want 1", len(records))
+	}
+	if len(rowErrors) != 3 {
+		t.Fatalf("Each() reported %d row errors, want 3", len(rowErrors))
+	}
+
+	wantLines := []int{3, 4, 5}
+	wantFields := []string{"Quantity", "Price", "fields"}
+	for i, rowErr := range rowErrors {
+		if rowErr.Line != wantLines[i] {
+			t.Errorf("rowErrors[%d].Line = %d, want %d", i, rowErr.Line, wantLines[i])
+		}
+		if rowErr.Field != wantFields[i] {
+			t.Errorf("rowErrors[%d].Field = %s, want %s", i, rowErr.Field, wantFields[i])
+		}
+	}
+}
+
+// TestReaderEach_ErrorHandlerAbort verifies that returning an error from the
+// ErrorHandler aborts iteration and surfaces the row's context.
+func TestReaderEach_ErrorHandlerAbort(t *testing.T) {
+	csvContent := streamHeader +
+		"2023-10-01,Laptop,2,1200.50\n" +
+		"2023-10-02,Mouse,Two,25.99\n" +
+		"2023-10-03,Keyboard,5,45.00\n"
+
+	reader := analyzer.NewReader(strings.NewReader(csvContent), analyzer.ParseOptions{
+		ErrorHandler: func(rowErr analyzer.RowError) error {
+			return rowErr
+		},
+	})
+
+	var records []analyzer.SaleRecord
+	err := reader.Each(func(record analyzer.SaleRecord) error {
+		records = append(records, record)
+		return nil
+	})
+
+	var rowErr analyzer.RowError
+	if !errors.As(err, &rowErr) {
+		t.Fatalf("Each() error = %v, want a RowError", err)
+	}
+	if rowErr.Line != 3 {
+		t.Errorf("RowError.Line = %d, want 3", rowErr.Line)
+	}
+	if len(records) != 1 {
+		t.Errorf("Each() kept %d records before aborting, want 1", len(records))
+	}
+}
+
+// TestParseCSVWithErrors verifies the convenience wrapper collects RowErrors
+// instead of printing warnings.
+func TestParseCSVWithErrors(t *testing.T) {
+	filePath := createTestFile(t, "with_errors.csv", "Date,Product,Quantity,Price\n"+
+		"2023-10-01,Laptop,2,1200.50\n"+
+		"2023-10-02,Mouse,Two,25.99\n")
+
+	records, rowErrors, err := analyzer.ParseCSVWithErrors(filePath)
+	if err != nil {
+		t.Fatalf("ParseCSVWithErrors() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ParseCSVWithErrors() got %d records, want 1", len(records))
+	}
+	if len(rowErrors) != 1 || rowErrors[0].Field != "Quantity" {
+		t.Fatalf("ParseCSVWithErrors() rowErrors = %+v, want one Quantity error", rowErrors)
+	}
+}
+
+// TestWriteRejectsCSV verifies that RowErrors are rendered as a readable CSV
+// with one row per rejected input line.
+func TestWriteRejectsCSV(t *testing.T) {
+	rowErrors := []analyzer.RowError{
+		{Line: 3, Row: []string{"2023-10-02", "Mouse", "Two", "25.99"}, Field: "Quantity", Err: errors.New(`strconv.Atoi: parsing "Two": invalid syntax`)},
+	}
+
+	var buf bytes.Buffer
+	if err := analyzer.WriteRejectsCSV(&buf, rowErrors); err != nil {
+		t.Fatalf("WriteRejectsCSV() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "line,field,error,row") {
+		t.Errorf("WriteRejectsCSV() missing header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Quantity") || !strings.Contains(out, "Mouse") {
+		t.Errorf("WriteRejectsCSV() missing row content, got:\n%s", out)
+	}
+}